@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type BookingStatus string
+
+const (
+	// BookingStatusActive is a normal journey reservation holding seats.
+	BookingStatusActive BookingStatus = "active"
+	// BookingStatusUnavailable is a synthetic booking blocking a vehicle for
+	// maintenance; it occupies the vehicle's full window like any booking.
+	BookingStatusUnavailable BookingStatus = "unavailable"
+	// BookingStatusCancelled is a booking ended early, via dropoff or
+	// DELETE /bookings/:id, before its window naturally expired.
+	BookingStatusCancelled BookingStatus = "cancelled"
+	// BookingStatusOld is a booking whose window has elapsed, set by the
+	// background expiry ticker.
+	BookingStatusOld BookingStatus = "old"
+)
+
+type Booking struct {
+	ID        int           `json:"id"`
+	VehicleID int           `json:"vehicle_id"`
+	GroupID   int           `json:"group_id"`
+	People    int           `json:"people"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Status    BookingStatus `json:"status"`
+}
+
+// occupiesCapacity reports whether a booking in this status still counts
+// against the vehicle's seats for overlap purposes.
+func (s BookingStatus) occupiesCapacity() bool {
+	return s == BookingStatusActive || s == BookingStatusUnavailable
+}
+
+type BookingRepository interface {
+	// OccupiedSeats sums the People of every booking on vehicleID whose
+	// window overlaps [from, to) and still occupies capacity.
+	OccupiedSeats(ctx context.Context, vehicleID int, from, to time.Time) int
+	// Reserve atomically checks that vehicle has at least people seats free
+	// throughout [from, to) and, if so, records an active booking for it.
+	Reserve(ctx context.Context, vehicle Vehicle, groupID, people int, from, to time.Time) (Booking, bool)
+	// BlockVehicle unconditionally records an unavailable booking occupying
+	// all seats, e.g. for maintenance, regardless of existing bookings.
+	BlockVehicle(ctx context.Context, vehicleID, seats int, from, to time.Time) Booking
+	GetBooking(ctx context.Context, bookingID int) (Booking, bool)
+	// CancelBooking marks a booking cancelled, freeing its seats immediately.
+	CancelBooking(ctx context.Context, bookingID int) bool
+	AllBookings(ctx context.Context) []Booking
+	// ExpireBookings transitions every active/unavailable booking whose
+	// window has elapsed by now to BookingStatusOld and returns them.
+	ExpireBookings(ctx context.Context, now time.Time) []Booking
+}
+
+type InMemoryBookingRepository struct {
+	mu       sync.RWMutex
+	nextID   int
+	bookings map[int]Booking
+}
+
+func NewInMemoryBookingRepository() *InMemoryBookingRepository {
+	return &InMemoryBookingRepository{bookings: make(map[int]Booking)}
+}
+
+func overlapsWindow(aFrom, aTo, bFrom, bTo time.Time) bool {
+	return aFrom.Before(bTo) && bFrom.Before(aTo)
+}
+
+func (repo *InMemoryBookingRepository) occupiedSeatsLocked(vehicleID int, from, to time.Time) int {
+	occupied := 0
+	for _, booking := range repo.bookings {
+		if booking.VehicleID != vehicleID || !booking.Status.occupiesCapacity() {
+			continue
+		}
+		if overlapsWindow(booking.From, booking.To, from, to) {
+			occupied += booking.People
+		}
+	}
+	return occupied
+}
+
+func (repo *InMemoryBookingRepository) OccupiedSeats(ctx context.Context, vehicleID int, from, to time.Time) int {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	return repo.occupiedSeatsLocked(vehicleID, from, to)
+}
+
+func (repo *InMemoryBookingRepository) Reserve(ctx context.Context, vehicle Vehicle, groupID, people int, from, to time.Time) (Booking, bool) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if vehicle.Seats-repo.occupiedSeatsLocked(vehicle.ID, from, to) < people {
+		return Booking{}, false
+	}
+	repo.nextID++
+	booking := Booking{
+		ID:        repo.nextID,
+		VehicleID: vehicle.ID,
+		GroupID:   groupID,
+		People:    people,
+		From:      from,
+		To:        to,
+		Status:    BookingStatusActive,
+	}
+	repo.bookings[booking.ID] = booking
+	return booking, true
+}
+
+func (repo *InMemoryBookingRepository) BlockVehicle(ctx context.Context, vehicleID, seats int, from, to time.Time) Booking {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.nextID++
+	booking := Booking{
+		ID:        repo.nextID,
+		VehicleID: vehicleID,
+		People:    seats,
+		From:      from,
+		To:        to,
+		Status:    BookingStatusUnavailable,
+	}
+	repo.bookings[booking.ID] = booking
+	return booking
+}
+
+func (repo *InMemoryBookingRepository) GetBooking(ctx context.Context, bookingID int) (Booking, bool) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	booking, exists := repo.bookings[bookingID]
+	return booking, exists
+}
+
+func (repo *InMemoryBookingRepository) CancelBooking(ctx context.Context, bookingID int) bool {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	booking, exists := repo.bookings[bookingID]
+	if !exists {
+		return false
+	}
+	booking.Status = BookingStatusCancelled
+	repo.bookings[bookingID] = booking
+	return true
+}
+
+func (repo *InMemoryBookingRepository) AllBookings(ctx context.Context) []Booking {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	all := make([]Booking, 0, len(repo.bookings))
+	for _, booking := range repo.bookings {
+		all = append(all, booking)
+	}
+	return all
+}
+
+func (repo *InMemoryBookingRepository) ExpireBookings(ctx context.Context, now time.Time) []Booking {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var expired []Booking
+	for id, booking := range repo.bookings {
+		if booking.Status.occupiesCapacity() && !booking.To.After(now) {
+			booking.Status = BookingStatusOld
+			repo.bookings[id] = booking
+			expired = append(expired, booking)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// eventBusWorkersPerType caps how many goroutines process a given event
+// type concurrently, so a slow subscriber queues up work instead of an
+// unbounded goroutine being spawned per Emit call.
+const eventBusWorkersPerType = 4
+
+type Event struct {
+	Type string
+	Data interface{}
+}
+
+type EventBus struct {
+	mu        sync.Mutex
+	listeners map[string][]func(Event)
+	pools     map[string]chan func()
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		listeners: make(map[string][]func(Event)),
+		pools:     make(map[string]chan func()),
+	}
+}
+
+func (eb *EventBus) Register(eventType string, listener func(Event)) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.listeners[eventType] = append(eb.listeners[eventType], listener)
+	if _, exists := eb.pools[eventType]; !exists {
+		pool := make(chan func(), eventBusWorkersPerType)
+		eb.pools[eventType] = pool
+		for i := 0; i < eventBusWorkersPerType; i++ {
+			go func() {
+				for job := range pool {
+					job()
+				}
+			}()
+		}
+	}
+}
+
+// Emit queues event on every listener registered for its type through a
+// bounded worker pool instead of spawning a goroutine per handler, so slow
+// subscribers can't leak goroutines. It returns ctx.Err() if the deadline
+// fires before every handler has been queued.
+func (eb *EventBus) Emit(ctx context.Context, event Event) error {
+	eb.mu.Lock()
+	handlers := eb.listeners[event.Type]
+	pool := eb.pools[event.Type]
+	eb.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler := handler
+		select {
+		case pool <- func() { handler(event) }:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery bounds how many encoded CSV rows are buffered before
+// flushing to the client, so the CSV output of a large fleet isn't held in
+// memory at once. The flattened rows themselves are all built first.
+const exportFlushEvery = 100
+
+// flatten recursively expands nested maps into dotted keys (e.g.
+// "vehicle.seats") so a future nested field on an exported struct shows up
+// in the CSV without any change here.
+func flatten(prefix string, in map[string]interface{}, out map[string]interface{}) {
+	for key, value := range in {
+		flatKey := key
+		if prefix != "" {
+			flatKey = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(flatKey, nested, out)
+			continue
+		}
+		out[flatKey] = value
+	}
+}
+
+// toFlatRow round-trips v through JSON so any struct's exported fields
+// become a plain map ready for flatten, without the exporter needing to
+// know its shape up front.
+func toFlatRow(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil, err
+	}
+	flat := make(map[string]interface{})
+	flatten("", row, flat)
+	return flat, nil
+}
+
+func flattenRows(items []interface{}) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		row, err := toFlatRow(item)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func assignmentRows(ctx context.Context, groupRepo GroupRepository, bookingRepo BookingRepository) ([]map[string]interface{}, error) {
+	items := make([]interface{}, 0)
+	for groupID, bookingID := range groupRepo.AllAssignments(ctx) {
+		booking, _ := bookingRepo.GetBooking(ctx, bookingID)
+		items = append(items, map[string]interface{}{
+			"group_id":   groupID,
+			"booking_id": bookingID,
+			"vehicle_id": booking.VehicleID,
+		})
+	}
+	return flattenRows(items)
+}
+
+// exportRows builds the flattened rows for one of the supported export
+// resources: vehicles, groups, bookings, or assignments.
+func exportRows(ctx context.Context, resource string, vehicleRepo VehicleRepository, groupRepo GroupRepository, bookingRepo BookingRepository) ([]map[string]interface{}, error) {
+	switch resource {
+	case "vehicles":
+		vehicles := vehicleRepo.GetAllVehicles(ctx)
+		items := make([]interface{}, len(vehicles))
+		for i, vehicle := range vehicles {
+			items[i] = vehicle
+		}
+		return flattenRows(items)
+	case "groups":
+		groups := groupRepo.WaitingGroups(ctx)
+		items := make([]interface{}, len(groups))
+		for i, group := range groups {
+			items[i] = group
+		}
+		return flattenRows(items)
+	case "bookings":
+		bookings := bookingRepo.AllBookings(ctx)
+		items := make([]interface{}, len(bookings))
+		for i, booking := range bookings {
+			items[i] = booking
+		}
+		return flattenRows(items)
+	case "assignments":
+		return assignmentRows(ctx, groupRepo, bookingRepo)
+	default:
+		return nil, fmt.Errorf("unknown export resource %q", resource)
+	}
+}
+
+// csvHeader unions every flattened row's keys into a stable, sorted header,
+// so rows with different keys (e.g. missing optional fields) still align.
+func csvHeader(rows []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var header []string
+	for _, row := range rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+	sort.Strings(header)
+	return header
+}
+
+// writeCSV streams rows to writer as CSV, flushing every exportFlushEvery
+// rows so the encoded output doesn't have to be buffered entirely in memory;
+// rows is already a full in-memory slice, since the header needs every
+// row's keys.
+func writeCSV(writer *csv.Writer, rows []map[string]interface{}) error {
+	header := csvHeader(rows)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		record := make([]string, len(header))
+		for j, key := range header {
+			if value, ok := row[key]; ok && value != nil {
+				record[j] = fmt.Sprintf("%v", value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		if (i+1)%exportFlushEvery == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func registerExportRoute(r *gin.Engine, vehicleRepo VehicleRepository, groupRepo GroupRepository, bookingRepo BookingRepository) {
+	r.GET("/export/:resource", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
+		resource := c.Param("resource")
+		rows, err := exportRows(ctx, resource, vehicleRepo, groupRepo, bookingRepo)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filename := fmt.Sprintf("export-%s-%d.csv", resource, time.Now().Unix())
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		if err := writeCSV(csv.NewWriter(c.Writer), rows); err != nil {
+			c.Status(http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenExpandsNestedMaps(t *testing.T) {
+	in := map[string]interface{}{
+		"id": 1,
+		"group": map[string]interface{}{
+			"id":     2,
+			"people": 3,
+		},
+	}
+	out := make(map[string]interface{})
+
+	flatten("", in, out)
+
+	assert.Equal(t, 1, out["id"])
+	assert.Equal(t, 2, out["group.id"])
+	assert.Equal(t, 3, out["group.people"])
+}
+
+func TestCSVHeaderUnionsKeysAcrossRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "seats": 4},
+		{"id": 2, "people": 3},
+	}
+
+	header := csvHeader(rows)
+
+	assert.Equal(t, []string{"id", "people", "seats"}, header)
+}
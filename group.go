@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Group struct {
+	ID     int `json:"id"`
+	People int `json:"people"`
+	// From and To are the group's requested journey window. They're not
+	// part of the public request/response payloads (those are {id, people}
+	// plus optional start/end on /journey); they're carried on the Group so
+	// a waitlisted group's window survives until PromoteWaiting can place it.
+	From time.Time `json:"-"`
+	To   time.Time `json:"-"`
+}
+
+type GroupRepository interface {
+	AddGroup(ctx context.Context, group Group)
+	RemoveGroup(ctx context.Context, groupID int) bool
+	FindGroup(ctx context.Context, groupID int) (Group, bool)
+	GetNextWaitingGroup(ctx context.Context) (Group, bool)
+	WaitingGroups(ctx context.Context) []Group
+	AssignGroup(ctx context.Context, groupID, bookingID int)
+	GetAssignedBooking(ctx context.Context, groupID int) (int, bool)
+	UnassignGroup(ctx context.Context, groupID int)
+	// AllAssignments returns a snapshot of every group ID currently mapped
+	// to a booking ID.
+	AllAssignments(ctx context.Context) map[int]int
+}
+
+type InMemoryGroupRepository struct {
+	mu             sync.RWMutex
+	groupsQueue    []Group
+	groupToBooking map[int]int
+}
+
+func (repo *InMemoryGroupRepository) AddGroup(ctx context.Context, group Group) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.groupsQueue = append(repo.groupsQueue, group)
+}
+
+func (repo *InMemoryGroupRepository) RemoveGroup(ctx context.Context, groupID int) bool {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, group := range repo.groupsQueue {
+		if group.ID == groupID {
+			repo.groupsQueue = append(repo.groupsQueue[:i], repo.groupsQueue[i+1:]...)
+			return true
+		}
+	}
+	delete(repo.groupToBooking, groupID)
+	return false
+}
+
+func (repo *InMemoryGroupRepository) FindGroup(ctx context.Context, groupID int) (Group, bool) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	for _, group := range repo.groupsQueue {
+		if group.ID == groupID {
+			return group, true
+		}
+	}
+	return Group{}, false
+}
+
+func (repo *InMemoryGroupRepository) GetNextWaitingGroup(ctx context.Context) (Group, bool) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	if len(repo.groupsQueue) > 0 {
+		return repo.groupsQueue[0], true
+	}
+	return Group{}, false
+}
+
+// WaitingGroups returns a snapshot of the queue in FIFO order, safe for a
+// caller to range over while the repository is mutated concurrently.
+func (repo *InMemoryGroupRepository) WaitingGroups(ctx context.Context) []Group {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	snapshot := make([]Group, len(repo.groupsQueue))
+	copy(snapshot, repo.groupsQueue)
+	return snapshot
+}
+
+// AssignGroup records that groupID rides under bookingID, removing it from
+// the waiting queue if it was there.
+func (repo *InMemoryGroupRepository) AssignGroup(ctx context.Context, groupID, bookingID int) {
+	repo.RemoveGroup(ctx, groupID)
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.groupToBooking[groupID] = bookingID
+}
+
+func (repo *InMemoryGroupRepository) GetAssignedBooking(ctx context.Context, groupID int) (int, bool) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	bookingID, exists := repo.groupToBooking[groupID]
+	return bookingID, exists
+}
+
+func (repo *InMemoryGroupRepository) UnassignGroup(ctx context.Context, groupID int) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.groupToBooking, groupID)
+}
+
+func (repo *InMemoryGroupRepository) AllAssignments(ctx context.Context) map[int]int {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	snapshot := make(map[int]int, len(repo.groupToBooking))
+	for groupID, bookingID := range repo.groupToBooking {
+		snapshot[groupID] = bookingID
+	}
+	return snapshot
+}
@@ -1,192 +1,137 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type Vehicle struct {
-	ID    int `json:"id"`
-	Seats int `json:"seats"`
-}
-
-type Group struct {
-	ID     int `json:"id"`
-	People int `json:"people"`
-}
-
-type VehicleRepository interface {
-	GetAllVehicles() []Vehicle
-	SaveVehicles(vehicles []Vehicle)
-	UpdateVehicleSeats(vehicleID, seats int)
-}
-
-type GroupRepository interface {
-	AddGroup(group Group)
-	RemoveGroup(groupID int) bool
-	FindGroup(groupID int) (Group, bool)
-	GetNextWaitingGroup() (Group, bool)
-}
-
-type VehicleService struct {
-	vehicleRepo VehicleRepository
-	eventBus    *EventBus
-}
-type Event struct {
-	Type string
-	Data interface{}
-}
-
-type EventBus struct {
-	listeners map[string][]func(Event)
-}
-
-func NewEventBus() *EventBus {
-	return &EventBus{
-		listeners: make(map[string][]func(Event)),
-	}
-}
-
-func (eb *EventBus) Register(eventType string, listener func(Event)) {
-	eb.listeners[eventType] = append(eb.listeners[eventType], listener)
-}
-func (eb *EventBus) Emit(event Event) {
-	if handlers, found := eb.listeners[event.Type]; found {
-		for _, handler := range handlers {
-			go handler(event)
-		}
-	}
-}
-
-func (vs *VehicleService) AssignVehicleToGroup(group Group) (int, bool) {
-	vehicles := vs.vehicleRepo.GetAllVehicles()
-	for i := range vehicles {
-		if vehicles[i].Seats >= group.People {
-			vs.vehicleRepo.UpdateVehicleSeats(vehicles[i].ID, vehicles[i].Seats-group.People)
-			vs.eventBus.Emit(Event{
-				Type: "VehicleAssigned",
-				Data: map[string]interface{}{
-					"group_id":   group.ID,
-					"vehicle_id": vehicles[i].ID,
-				},
-			})
-			return vehicles[i].ID, true
-		}
-	}
-	return 0, false
-}
-
-func (vs *VehicleService) ReleaseVehicleSeats(vehicleID, seats int) {
-	vs.vehicleRepo.UpdateVehicleSeats(vehicleID, seats)
-}
-
-type InMemoryVehicleRepository struct {
-	vehicles []Vehicle
-}
-
-func (repo *InMemoryVehicleRepository) GetAllVehicles() []Vehicle {
-	return repo.vehicles
-}
-
-func (repo *InMemoryVehicleRepository) SaveVehicles(vehicles []Vehicle) {
-	repo.vehicles = vehicles
-}
-
-func (repo *InMemoryVehicleRepository) UpdateVehicleSeats(vehicleID, seats int) {
-	for i := range repo.vehicles {
-		if repo.vehicles[i].ID == vehicleID {
-			repo.vehicles[i].Seats = seats
-			break
+// defaultRequestTimeout is the per-request context deadline the handlers
+// derive, overridable via REQUEST_TIMEOUT_MS for slower environments. The
+// /journey, /dropoff and DELETE /bookings/:id handlers return 504 once it
+// has fired.
+const defaultRequestTimeout = 2 * time.Second
+
+// defaultJourneyDuration is the journey window used when /journey omits
+// start/end, matching the old instantaneous-seat-hold behavior as closely
+// as a time-boxed booking can.
+const defaultJourneyDuration = time.Hour
+
+// bookingExpiryInterval is how often the background ticker sweeps for
+// bookings whose window has elapsed.
+const bookingExpiryInterval = time.Minute
+
+// requestTimeout returns the per-request deadline, overridable via
+// REQUEST_TIMEOUT_MS so slower deployments can relax it without a rebuild.
+func requestTimeout() time.Duration {
+	if raw := os.Getenv("REQUEST_TIMEOUT_MS"); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
 		}
 	}
-}
-
-type InMemoryGroupRepository struct {
-	groupsQueue []Group
-	groupToCar  map[int]int
-}
-
-func (repo *InMemoryGroupRepository) AddGroup(group Group) {
-	repo.groupsQueue = append(repo.groupsQueue, group)
-}
-
-func (repo *InMemoryGroupRepository) RemoveGroup(groupID int) bool {
-	for i, group := range repo.groupsQueue {
-		if group.ID == groupID {
-			repo.groupsQueue = append(repo.groupsQueue[:i], repo.groupsQueue[i+1:]...)
-			return true
+	return defaultRequestTimeout
+}
+
+// startExpiryTicker periodically transitions elapsed bookings to
+// BookingStatusOld and promotes any waiting groups the freed capacity can
+// now seat, so vehicles are released without needing an explicit dropoff.
+func startExpiryTicker(vehicleService *VehicleService, bookingRepo BookingRepository, interval time.Duration) *time.Ticker {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout())
+			bookingRepo.ExpireBookings(ctx, time.Now())
+			vehicleService.PromoteWaiting(ctx)
+			cancel()
 		}
-	}
-	delete(repo.groupToCar, groupID)
-	return false
+	}()
+	return ticker
 }
 
-func (repo *InMemoryGroupRepository) FindGroup(groupID int) (Group, bool) {
-	for _, group := range repo.groupsQueue {
-		if group.ID == groupID {
-			return group, true
-		}
-	}
-	return Group{}, false
-}
-
-func (repo *InMemoryGroupRepository) GetNextWaitingGroup() (Group, bool) {
-	if len(repo.groupsQueue) > 0 {
-		return repo.groupsQueue[0], true
-	}
-	return Group{}, false
-}
-
-func main() {
-	eventBus := NewEventBus()
-	vehicleRepo := &InMemoryVehicleRepository{}
-	groupRepo := &InMemoryGroupRepository{groupToCar: make(map[int]int)}
-	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, eventBus: eventBus}
-	// log when a group is successfully assigned to a vehicle, this include logging, but is only for the
-	// challenge in a real situation this can sending an email, SMS, or other notifications
-	eventBus.Register("VehicleAssigned", func(e Event) {
-		data := e.Data.(map[string]interface{})
-		groupID := data["group_id"].(int)
-		vehicleID := data["vehicle_id"].(int)
-		log.Printf("Group %d assigned to Vehicle %d\n", groupID, vehicleID)
-	})
-	// one event handler here to demonstrate the idea, more can be added as needed in a real situation
-	r := gin.Default()
+// registerRoutes wires every HTTP endpoint onto r against the given
+// repositories and service. main() and the test harness both call this
+// instead of keeping two hand-written copies of the handlers in sync.
+func registerRoutes(r *gin.Engine, vehicleRepo VehicleRepository, groupRepo GroupRepository, bookingRepo BookingRepository, vehicleService *VehicleService) {
 	// health check endpoint to verify the server is ready
 	r.GET("/status", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ready"})
 	})
+	// endpoint to export operational state as CSV, for reporting/analytics
+	registerExportRoute(r, vehicleRepo, groupRepo, bookingRepo)
 	// endpoint to register electric vehicles
 	r.PUT("/evs", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
 		var newVehicles []Vehicle
 		if err := c.ShouldBindJSON(&newVehicles); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
 			return
 		}
-		vehicleRepo.SaveVehicles(newVehicles)
-		groupRepo.groupsQueue = []Group{}
-		groupRepo.groupToCar = make(map[int]int)
+		vehicleRepo.SaveVehicles(ctx, newVehicles)
+		// re-registering the fleet invalidates every outstanding group, waiting
+		// or assigned, so clear both through the interface rather than reaching
+		// into a concrete repository's fields.
+		for _, group := range groupRepo.WaitingGroups(ctx) {
+			groupRepo.RemoveGroup(ctx, group.ID)
+		}
+		for groupID := range groupRepo.AllAssignments(ctx) {
+			groupRepo.UnassignGroup(ctx, groupID)
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "EVs registered successfully"})
 	})
 	// endpoint to handle journey requests
 	r.POST("/journey", func(c *gin.Context) {
-		var group Group
-		if err := c.ShouldBindJSON(&group); err != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
+		var payload struct {
+			Group
+			Start *time.Time `json:"start"`
+			End   *time.Time `json:"end"`
+		}
+		if err := c.ShouldBindJSON(&payload); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
 			return
 		}
-		if carID, assigned := vehicleService.AssignVehicleToGroup(group); assigned {
-			groupRepo.groupToCar[group.ID] = carID
-			c.JSON(http.StatusOK, gin.H{"message": "Journey started", "car_id": carID})
+		group := payload.Group
+		if payload.Start != nil {
+			group.From = *payload.Start
+		} else {
+			group.From = time.Now()
+		}
+		if payload.End != nil {
+			group.To = *payload.End
+		} else {
+			group.To = group.From.Add(defaultJourneyDuration)
+		}
+
+		booking, assigned := vehicleService.AssignVehicleToGroup(ctx, group)
+		// Link the group to its booking as soon as AssignVehicleToGroup reports
+		// success, before looking at ctx.Err(): the reservation already holds
+		// the seat at this point, so a deadline that fires afterward (e.g.
+		// while the event bus was still draining) must not orphan it.
+		if assigned {
+			groupRepo.AssignGroup(ctx, group.ID, booking.ID)
+		}
+		if ctx.Err() != nil {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out"})
+			return
+		}
+		if assigned {
+			c.JSON(http.StatusOK, gin.H{"message": "Journey started", "car_id": booking.VehicleID, "booking_id": booking.ID})
 			return
 		}
-		groupRepo.AddGroup(group)
+		groupRepo.AddGroup(ctx, group)
 		c.JSON(http.StatusAccepted, gin.H{"message": "Added to waitlist"})
 	})
-	// endpoint to drop off a group and release vehicle seats
+	// endpoint to drop off a group and release its booked seats
 	r.POST("/dropoff", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
 		var request struct {
 			ID int `json:"id"`
 		}
@@ -194,17 +139,24 @@ func main() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
 			return
 		}
-		if carID, exists := groupRepo.groupToCar[request.ID]; exists {
-			group, _ := groupRepo.FindGroup(request.ID)
-			vehicleService.ReleaseVehicleSeats(carID, group.People)
-			delete(groupRepo.groupToCar, request.ID)
-			c.JSON(http.StatusOK, gin.H{"message": "Group dropped off"})
+		bookingID, exists := groupRepo.GetAssignedBooking(ctx, request.ID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
 			return
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		bookingRepo.CancelBooking(ctx, bookingID)
+		groupRepo.UnassignGroup(ctx, request.ID)
+		vehicleService.PromoteWaiting(ctx)
+		if ctx.Err() != nil {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Group dropped off"})
 	})
 
 	r.POST("/locate", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
 		var request struct {
 			ID int `json:"id"`
 		}
@@ -212,12 +164,90 @@ func main() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
 			return
 		}
-		if carID, exists := groupRepo.groupToCar[request.ID]; exists {
-			c.JSON(http.StatusOK, gin.H{"car_id": carID})
+		bookingID, exists := groupRepo.GetAssignedBooking(ctx, request.ID)
+		if !exists {
+			c.JSON(http.StatusNoContent, nil)
 			return
 		}
-		c.JSON(http.StatusNoContent, nil)
+		booking, _ := bookingRepo.GetBooking(ctx, bookingID)
+		c.JSON(http.StatusOK, gin.H{"car_id": booking.VehicleID})
 	})
 
+	// endpoint to block a vehicle for maintenance over a time window
+	r.POST("/vehicles/:id/unavailability", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
+		vehicleID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid vehicle id"})
+			return
+		}
+		var request struct {
+			StartDate time.Time `json:"startdate"`
+			EndDate   time.Time `json:"enddate"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+			return
+		}
+		booking, found := vehicleService.BlockVehicle(ctx, vehicleID, request.StartDate, request.EndDate)
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found"})
+			return
+		}
+		c.JSON(http.StatusCreated, booking)
+	})
+
+	// endpoint to cancel a booking before its window naturally expires
+	r.DELETE("/bookings/:id", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), requestTimeout())
+		defer cancel()
+		bookingID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking id"})
+			return
+		}
+		booking, exists := bookingRepo.GetBooking(ctx, bookingID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+			return
+		}
+		bookingRepo.CancelBooking(ctx, bookingID)
+		if _, assigned := groupRepo.GetAssignedBooking(ctx, booking.GroupID); assigned {
+			groupRepo.UnassignGroup(ctx, booking.GroupID)
+		}
+		vehicleService.PromoteWaiting(ctx)
+		if ctx.Err() != nil {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled"})
+	})
+}
+
+func main() {
+	storageCfg := loadStorageConfig()
+	vehicleRepo, groupRepo, bookingRepo, closeStorage, err := newRepositories(storageCfg)
+	if err != nil {
+		log.Fatalf("storage: %v", err)
+	}
+	defer closeStorage()
+
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+	// log when a group is successfully assigned to a vehicle, this include logging, but is only for the
+	// challenge in a real situation this can sending an email, SMS, or other notifications
+	eventBus.Register("VehicleAssigned", func(e Event) {
+		data := e.Data.(map[string]interface{})
+		groupID := data["group_id"].(int)
+		vehicleID := data["vehicle_id"].(int)
+		log.Printf("Group %d assigned to Vehicle %d\n", groupID, vehicleID)
+	})
+	// one event handler here to demonstrate the idea, more can be added as needed in a real situation
+	ticker := startExpiryTicker(vehicleService, bookingRepo, bookingExpiryInterval)
+	defer ticker.Stop()
+
+	r := gin.Default()
+	registerRoutes(r, vehicleRepo, groupRepo, bookingRepo, vehicleService)
 	r.Run(":80")
 }
@@ -2,23 +2,30 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRegisterEVs(t *testing.T) {
 	vehicleRepo := &InMemoryVehicleRepository{}
-	groupRepo := &InMemoryGroupRepository{groupToCar: make(map[int]int)}
-	vehicleService := &VehicleService{vehicleRepo: vehicleRepo}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo}
 	eventBus := NewEventBus()
 	vehicleService.eventBus = eventBus
 
-	r := setupRouter(vehicleRepo, groupRepo, vehicleService, eventBus)
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
 
 	payload := `[{"id":1,"seats":4},{"id":2,"seats":6}]`
 	req, _ := http.NewRequest("PUT", "/evs", bytes.NewBuffer([]byte(payload)))
@@ -29,18 +36,19 @@ func TestRegisterEVs(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "EVs registered successfully")
-	assert.Len(t, vehicleRepo.GetAllVehicles(), 2)
+	assert.Len(t, vehicleRepo.GetAllVehicles(context.Background()), 2)
 }
 
 func TestJourneyRequest(t *testing.T) {
 	vehicleRepo := &InMemoryVehicleRepository{
 		vehicles: []Vehicle{{ID: 1, Seats: 4}},
 	}
-	groupRepo := &InMemoryGroupRepository{groupToCar: make(map[int]int)}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
 	eventBus := NewEventBus()
-	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, eventBus: eventBus}
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
 
-	r := setupRouter(vehicleRepo, groupRepo, vehicleService, eventBus)
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
 
 	payload := `{"id":1,"people":3}`
 	req, _ := http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(payload)))
@@ -51,21 +59,60 @@ func TestJourneyRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "Journey started")
-	assert.Equal(t, 1, groupRepo.groupToCar[1])
+	bookingID, exists := groupRepo.GetAssignedBooking(context.Background(), 1)
+	assert.True(t, exists)
+	booking, _ := bookingRepo.GetBooking(context.Background(), bookingID)
+	assert.Equal(t, 1, booking.VehicleID)
 }
 
-func TestDropOffGroup(t *testing.T) {
+func TestJourneyWithExplicitWindow(t *testing.T) {
 	vehicleRepo := &InMemoryVehicleRepository{
 		vehicles: []Vehicle{{ID: 1, Seats: 4}},
 	}
-	groupRepo := &InMemoryGroupRepository{
-		groupsQueue: []Group{{ID: 1, People: 3}},
-		groupToCar:  map[int]int{1: 1},
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	payload := `{"id":1,"people":3,"start":"2026-08-01T09:00:00Z","end":"2026-08-01T10:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// A second group overlapping the same window can't fit alongside the
+	// first (3 + 3 > 4 seats), but a non-overlapping window is free to use.
+	overlapping := `{"id":2,"people":3,"start":"2026-08-01T09:30:00Z","end":"2026-08-01T10:30:00Z"}`
+	req, _ = http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(overlapping)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	afterward := `{"id":3,"people":3,"start":"2026-08-01T10:00:00Z","end":"2026-08-01T11:00:00Z"}`
+	req, _ = http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(afterward)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestDropOffGroup(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
 	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	booking, _ := bookingRepo.Reserve(context.Background(), Vehicle{ID: 1, Seats: 4}, 1, 3, time.Now(), time.Now().Add(time.Hour))
+	groupRepo.groupsQueue = []Group{{ID: 1, People: 3}}
+	groupRepo.groupToBooking = map[int]int{1: booking.ID}
 	eventBus := NewEventBus()
-	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, eventBus: eventBus}
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
 
-	r := setupRouter(vehicleRepo, groupRepo, vehicleService, eventBus)
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
 
 	payload := `{"id":1}`
 	req, _ := http.NewRequest("POST", "/dropoff", bytes.NewBuffer([]byte(payload)))
@@ -76,19 +123,22 @@ func TestDropOffGroup(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "Group dropped off")
+	cancelled, _ := bookingRepo.GetBooking(context.Background(), booking.ID)
+	assert.Equal(t, BookingStatusCancelled, cancelled.Status)
 }
 
 func TestLocateGroup(t *testing.T) {
 	vehicleRepo := &InMemoryVehicleRepository{
 		vehicles: []Vehicle{{ID: 1, Seats: 4}},
 	}
-	groupRepo := &InMemoryGroupRepository{
-		groupToCar: map[int]int{1: 1},
-	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	booking, _ := bookingRepo.Reserve(context.Background(), Vehicle{ID: 1, Seats: 4}, 1, 3, time.Now(), time.Now().Add(time.Hour))
+	groupRepo.groupToBooking = map[int]int{1: booking.ID}
 	eventBus := NewEventBus()
-	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, eventBus: eventBus}
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
 
-	r := setupRouter(vehicleRepo, groupRepo, vehicleService, eventBus)
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
 
 	payload := `{"id":1}`
 	req, _ := http.NewRequest("POST", "/locate", bytes.NewBuffer([]byte(payload)))
@@ -101,7 +151,236 @@ func TestLocateGroup(t *testing.T) {
 	assert.Contains(t, resp.Body.String(), `"car_id":1`)
 }
 
-func setupRouter(vehicleRepo *InMemoryVehicleRepository, groupRepo *InMemoryGroupRepository, vehicleService *VehicleService, eventBus *EventBus) *gin.Engine {
+func TestDropOffPromotesWaitingGroup(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	// The only vehicle is taken by a large group, so a small group behind it waits.
+	largeGroup := `{"id":1,"people":4}`
+	req, _ := http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(largeGroup)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	smallGroup := `{"id":2,"people":2}`
+	req, _ = http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(smallGroup)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	// Dropping off the large group frees the vehicle, which should immediately
+	// promote the waiting small group without a second /journey call.
+	dropoff := `{"id":1}`
+	req, _ = http.NewRequest("POST", "/dropoff", bytes.NewBuffer([]byte(dropoff)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	bookingID, exists := groupRepo.GetAssignedBooking(context.Background(), 2)
+	assert.True(t, exists)
+	booking, _ := bookingRepo.GetBooking(context.Background(), bookingID)
+	assert.Equal(t, 1, booking.VehicleID)
+}
+
+func TestUnavailabilityBlocksVehicle(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	payload := `{"startdate":"2026-08-01T00:00:00Z","enddate":"2026-08-02T00:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/vehicles/1/unavailability", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	journey := `{"id":1,"people":2,"start":"2026-08-01T10:00:00Z","end":"2026-08-01T11:00:00Z"}`
+	req, _ = http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(journey)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusAccepted, resp.Code, "a vehicle under maintenance shouldn't be assignable")
+}
+
+func TestDeleteBookingCancelsAndPromotes(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	largeGroup := `{"id":1,"people":4}`
+	req, _ := http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(largeGroup)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	bookingID, _ := groupRepo.GetAssignedBooking(context.Background(), 1)
+
+	smallGroup := `{"id":2,"people":2}`
+	req, _ = http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(smallGroup)))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	req, _ = http.NewRequest("DELETE", "/bookings/"+strconv.Itoa(bookingID), nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	_, exists := groupRepo.GetAssignedBooking(context.Background(), 2)
+	assert.True(t, exists, "freed capacity should immediately promote the waiting group")
+}
+
+// TestConcurrentAssignmentOnlySeatsOneGroup fires concurrent
+// AssignVehicleToGroup calls at a vehicle with exactly enough seats for one
+// of them, and asserts Reserve's locking lets exactly one group win the
+// last seat instead of double-booking it.
+func TestConcurrentAssignmentOnlySeatsOneGroup(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	const contenders = 20
+	from, to := time.Now(), time.Now().Add(time.Hour)
+	results := make(chan bool, contenders)
+	var start sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < contenders; i++ {
+		go func(groupID int) {
+			start.Wait()
+			group := Group{ID: groupID, People: 4, From: from, To: to}
+			_, assigned := vehicleService.AssignVehicleToGroup(context.Background(), group)
+			results <- assigned
+		}(i + 1)
+	}
+	start.Done()
+
+	assignedCount := 0
+	for i := 0; i < contenders; i++ {
+		if <-results {
+			assignedCount++
+		}
+	}
+	assert.Equal(t, 1, assignedCount, "only one group should fit the vehicle's last seat")
+	assert.Equal(t, 4, bookingRepo.OccupiedSeats(context.Background(), 1, from, to))
+}
+
+// TestJourneyHonorsReservationPastDeadline saturates the VehicleAssigned
+// worker pool so Emit can't queue every listener before the request
+// deadline fires. The /journey handler must still link the group to its
+// already-successful reservation instead of returning 504 and leaving the
+// booking orphaned with no group pointing at it.
+func TestJourneyHonorsReservationPastDeadline(t *testing.T) {
+	require.NoError(t, os.Setenv("REQUEST_TIMEOUT_MS", "20"))
+	defer os.Unsetenv("REQUEST_TIMEOUT_MS")
+
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	// The bounded pool has eventBusWorkersPerType workers plus a buffer of
+	// the same size, so it takes more than double that many blocking
+	// listeners before a further Emit send actually has to wait.
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 2*eventBusWorkersPerType+1; i++ {
+		eventBus.Register("VehicleAssigned", func(e Event) {
+			<-release
+		})
+	}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	payload := `{"id":1,"people":2}`
+	req, _ := http.NewRequest("POST", "/journey", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+
+	bookingID, assigned := groupRepo.GetAssignedBooking(context.Background(), 1)
+	require.True(t, assigned, "a reservation that already succeeded must not be orphaned by a deadline hit during event emission")
+	booking, found := bookingRepo.GetBooking(context.Background(), bookingID)
+	require.True(t, found)
+	assert.Equal(t, BookingStatusActive, booking.Status)
+}
+
+func TestExportVehicles(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{
+		vehicles: []Vehicle{{ID: 1, Seats: 4}, {ID: 2, Seats: 6}},
+	}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	req, _ := http.NewRequest("GET", "/export/vehicles", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "export-vehicles-")
+	body := resp.Body.String()
+	assert.Contains(t, body, "id,seats")
+	assert.Contains(t, body, "1,4")
+	assert.Contains(t, body, "2,6")
+}
+
+func TestExportUnknownResource(t *testing.T) {
+	vehicleRepo := &InMemoryVehicleRepository{}
+	groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+	bookingRepo := NewInMemoryBookingRepository()
+	eventBus := NewEventBus()
+	vehicleService := &VehicleService{vehicleRepo: vehicleRepo, groupRepo: groupRepo, bookingRepo: bookingRepo, eventBus: eventBus}
+
+	r := setupRouter(vehicleRepo, groupRepo, bookingRepo, vehicleService, eventBus)
+
+	req, _ := http.NewRequest("GET", "/export/unknown", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// setupRouter builds a test router against registerRoutes, the same route
+// registration main() uses, so these httptest-driven tests exercise the
+// exact handlers that ship instead of a hand-maintained copy.
+func setupRouter(vehicleRepo *InMemoryVehicleRepository, groupRepo *InMemoryGroupRepository, bookingRepo BookingRepository, vehicleService *VehicleService, eventBus *EventBus) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	eventBus.Register("VehicleAssigned", func(e Event) {
@@ -118,73 +397,6 @@ func setupRouter(vehicleRepo *InMemoryVehicleRepository, groupRepo *InMemoryGrou
 		}
 		log.Printf("Group %d assigned to Vehicle %d\n", groupID, vehicleID)
 	})
-	r.PUT("/evs", func(c *gin.Context) {
-		var newVehicles []Vehicle
-		if err := c.ShouldBindJSON(&newVehicles); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
-			return
-		}
-		vehicleRepo.SaveVehicles(newVehicles)
-		groupRepo.groupsQueue = []Group{}
-		groupRepo.groupToCar = make(map[int]int)
-		c.JSON(http.StatusOK, gin.H{"message": "EVs registered successfully"})
-	})
-
-	r.POST("/journey", func(c *gin.Context) {
-		var group Group
-		if err := c.ShouldBindJSON(&group); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
-			return
-		}
-		if carID, assigned := vehicleService.AssignVehicleToGroup(group); assigned {
-			groupRepo.groupToCar[group.ID] = carID
-			eventBus.Emit(Event{
-				Type: "VehicleAssigned",
-				Data: map[string]interface{}{
-					"group_id": group.ID,
-					"car_id":   carID,
-				},
-			})
-
-			c.JSON(http.StatusOK, gin.H{"message": "Journey started", "car_id": carID})
-			return
-		}
-		groupRepo.AddGroup(group)
-		c.JSON(http.StatusAccepted, gin.H{"message": "Added to waitlist"})
-	})
-
-	r.POST("/dropoff", func(c *gin.Context) {
-		var request struct {
-			ID int `json:"id"`
-		}
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
-			return
-		}
-		if carID, exists := groupRepo.groupToCar[request.ID]; exists {
-			group, _ := groupRepo.FindGroup(request.ID)
-			vehicleService.ReleaseVehicleSeats(carID, group.People)
-			delete(groupRepo.groupToCar, request.ID)
-			c.JSON(http.StatusOK, gin.H{"message": "Group dropped off"})
-			return
-		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
-	})
-
-	r.POST("/locate", func(c *gin.Context) {
-		var request struct {
-			ID int `json:"id"`
-		}
-		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
-			return
-		}
-		if carID, exists := groupRepo.groupToCar[request.ID]; exists {
-			c.JSON(http.StatusOK, gin.H{"car_id": carID})
-			return
-		}
-		c.JSON(http.StatusNoContent, nil)
-	})
-
+	registerRoutes(r, vehicleRepo, groupRepo, bookingRepo, vehicleService)
 	return r
 }
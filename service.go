@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+type VehicleService struct {
+	vehicleRepo VehicleRepository
+	groupRepo   GroupRepository
+	bookingRepo BookingRepository
+	eventBus    *EventBus
+}
+
+// bestFitVehicle returns the vehicle with the least free capacity left over
+// after seating people throughout [from, to), breaking ties by the lowest
+// vehicle ID. Allocating tightly like this keeps larger vehicles free for
+// larger groups instead of handing them out first-come-first-served.
+func (vs *VehicleService) bestFitVehicle(ctx context.Context, vehicles []Vehicle, people int, from, to time.Time) (Vehicle, bool) {
+	var best Vehicle
+	bestRemaining := -1
+	found := false
+	for _, vehicle := range vehicles {
+		free := vehicle.Seats - vs.bookingRepo.OccupiedSeats(ctx, vehicle.ID, from, to)
+		remaining := free - people
+		if remaining < 0 {
+			continue
+		}
+		if !found || remaining < bestRemaining || (remaining == bestRemaining && vehicle.ID < best.ID) {
+			best = vehicle
+			bestRemaining = remaining
+			found = true
+		}
+	}
+	return best, found
+}
+
+// AssignVehicleToGroup picks the best-fit vehicle for group's journey window
+// and reserves it. The capacity check and the reservation aren't one atomic
+// step, so if another request reserves the chosen vehicle's seats first,
+// Reserve fails and the search retries against the now-current bookings.
+func (vs *VehicleService) AssignVehicleToGroup(ctx context.Context, group Group) (Booking, bool) {
+	for {
+		if ctx.Err() != nil {
+			return Booking{}, false
+		}
+		vehicles := vs.vehicleRepo.GetAllVehicles(ctx)
+		vehicle, found := vs.bestFitVehicle(ctx, vehicles, group.People, group.From, group.To)
+		if !found {
+			return Booking{}, false
+		}
+		booking, reserved := vs.bookingRepo.Reserve(ctx, vehicle, group.ID, group.People, group.From, group.To)
+		if !reserved {
+			continue
+		}
+		vs.eventBus.Emit(ctx, Event{
+			Type: "VehicleAssigned",
+			Data: map[string]interface{}{
+				"group_id":   group.ID,
+				"vehicle_id": vehicle.ID,
+			},
+		})
+		return booking, true
+	}
+}
+
+// BlockVehicle takes a vehicle out of service for [from, to) by recording an
+// unavailable booking against it, e.g. for maintenance.
+func (vs *VehicleService) BlockVehicle(ctx context.Context, vehicleID int, from, to time.Time) (Booking, bool) {
+	for _, vehicle := range vs.vehicleRepo.GetAllVehicles(ctx) {
+		if vehicle.ID == vehicleID {
+			return vs.bookingRepo.BlockVehicle(ctx, vehicleID, vehicle.Seats, from, to), true
+		}
+	}
+	return Booking{}, false
+}
+
+// PromoteWaiting re-scans the waiting queue, in FIFO order, assigning a
+// best-fit vehicle to every group it can seat in its requested window. It
+// keeps sweeping the queue until a full pass promotes nobody, so a single
+// dropoff or cancellation can unblock several waiting groups in one call.
+func (vs *VehicleService) PromoteWaiting(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		promoted := false
+		for _, group := range vs.groupRepo.WaitingGroups(ctx) {
+			booking, assigned := vs.AssignVehicleToGroup(ctx, group)
+			if !assigned {
+				continue
+			}
+			vs.groupRepo.AssignGroup(ctx, group.ID, booking.ID)
+			promoted = true
+		}
+		if !promoted {
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+type StorageBackend string
+
+const (
+	StorageMemory   StorageBackend = "memory"
+	StorageRedis    StorageBackend = "redis"
+	StoragePostgres StorageBackend = "postgres"
+)
+
+// storageConfig picks which VehicleRepository/GroupRepository
+// implementation main wires up. It's resolved from the --storage flag; when
+// that is memory (the default, whether given or not) and STORAGE_DSN is set,
+// a redis:// or postgres:// scheme on the DSN picks that backend instead.
+type storageConfig struct {
+	Backend StorageBackend
+	DSN     string
+}
+
+func loadStorageConfig() storageConfig {
+	backend := flag.String("storage", string(StorageMemory), "storage backend: memory, redis, or postgres")
+	flag.Parse()
+
+	cfg := storageConfig{Backend: StorageBackend(*backend), DSN: os.Getenv("STORAGE_DSN")}
+	if cfg.DSN != "" && cfg.Backend == StorageMemory {
+		switch {
+		case strings.HasPrefix(cfg.DSN, "redis://"):
+			cfg.Backend = StorageRedis
+		case strings.HasPrefix(cfg.DSN, "postgres://"), strings.HasPrefix(cfg.DSN, "postgresql://"):
+			cfg.Backend = StoragePostgres
+		}
+	}
+	return cfg
+}
+
+// newRepositories builds the VehicleRepository, GroupRepository, and
+// BookingRepository for cfg.Backend, plus a close func to release the
+// underlying connection. The redis and postgres backends keep the fleet, the
+// waiting queue, and which vehicle a group rode across a restart; the memory
+// backend keeps none of it.
+func newRepositories(cfg storageConfig) (VehicleRepository, GroupRepository, BookingRepository, func() error, error) {
+	switch cfg.Backend {
+	case StorageRedis:
+		client := redis.NewClient(&redis.Options{Addr: strings.TrimPrefix(cfg.DSN, "redis://")})
+		return NewRedisVehicleRepository(client), NewRedisGroupRepository(client), NewRedisBookingRepository(client), client.Close, nil
+
+	case StoragePostgres:
+		db, err := sql.Open("postgres", cfg.DSN)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("open postgres: %w", err)
+		}
+		return NewPostgresVehicleRepository(db), NewPostgresGroupRepository(db), NewPostgresBookingRepository(db), db.Close, nil
+
+	case StorageMemory, "":
+		vehicleRepo := &InMemoryVehicleRepository{}
+		groupRepo := &InMemoryGroupRepository{groupToBooking: make(map[int]int)}
+		bookingRepo := NewInMemoryBookingRepository()
+		return vehicleRepo, groupRepo, bookingRepo, func() error { return nil }, nil
+
+	default:
+		return nil, nil, nil, nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
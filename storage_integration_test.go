@@ -0,0 +1,150 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// testVehicleRepository runs the same behavioral checks against any
+// VehicleRepository implementation, in-memory or otherwise.
+func testVehicleRepository(t *testing.T, repo VehicleRepository) {
+	ctx := context.Background()
+	repo.SaveVehicles(ctx, []Vehicle{{ID: 1, Seats: 4}, {ID: 2, Seats: 6}})
+
+	vehicles := repo.GetAllVehicles(ctx)
+	assert.Len(t, vehicles, 2)
+}
+
+// testGroupRepository runs the same behavioral checks against any
+// GroupRepository implementation.
+func testGroupRepository(t *testing.T, repo GroupRepository) {
+	ctx := context.Background()
+	group := Group{ID: 1, People: 3, From: time.Now(), To: time.Now().Add(time.Hour)}
+
+	repo.AddGroup(ctx, group)
+	next, found := repo.GetNextWaitingGroup(ctx)
+	require.True(t, found)
+	assert.Equal(t, group.ID, next.ID)
+
+	repo.AssignGroup(ctx, group.ID, 99)
+	_, stillWaiting := repo.FindGroup(ctx, group.ID)
+	assert.False(t, stillWaiting)
+
+	bookingID, assigned := repo.GetAssignedBooking(ctx, group.ID)
+	require.True(t, assigned)
+	assert.Equal(t, 99, bookingID)
+
+	repo.UnassignGroup(ctx, group.ID)
+	_, assigned = repo.GetAssignedBooking(ctx, group.ID)
+	assert.False(t, assigned)
+}
+
+// testBookingRepository runs the same behavioral checks against any
+// BookingRepository implementation.
+func testBookingRepository(t *testing.T, repo BookingRepository) {
+	ctx := context.Background()
+	vehicle := Vehicle{ID: 1, Seats: 4}
+	from := time.Now()
+	to := from.Add(time.Hour)
+
+	booking, reserved := repo.Reserve(ctx, vehicle, 1, 4, from, to)
+	require.True(t, reserved)
+	assert.Equal(t, 4, repo.OccupiedSeats(ctx, vehicle.ID, from, to))
+
+	_, reserved = repo.Reserve(ctx, vehicle, 2, 1, from, to)
+	assert.False(t, reserved, "vehicle is already full for this window")
+
+	fetched, found := repo.GetBooking(ctx, booking.ID)
+	require.True(t, found)
+	assert.Equal(t, booking.VehicleID, fetched.VehicleID)
+
+	require.True(t, repo.CancelBooking(ctx, booking.ID))
+	assert.Equal(t, 0, repo.OccupiedSeats(ctx, vehicle.ID, from, to))
+
+	block := repo.BlockVehicle(ctx, vehicle.ID, vehicle.Seats, from, to)
+	assert.Equal(t, BookingStatusUnavailable, block.Status)
+	assert.Equal(t, vehicle.Seats, repo.OccupiedSeats(ctx, vehicle.ID, from, to))
+
+	expired := repo.ExpireBookings(ctx, to.Add(time.Minute))
+	require.Len(t, expired, 1)
+	assert.Equal(t, BookingStatusOld, expired[0].Status)
+}
+
+func TestRedisRepositories(t *testing.T) {
+	ctx := context.Background()
+	container, err := tcredis.Run(ctx, "redis:7")
+	if err != nil {
+		t.Skipf("redis container unavailable: %v", err)
+	}
+	defer container.Terminate(ctx)
+
+	uri, err := container.ConnectionString(ctx)
+	require.NoError(t, err)
+	opts, err := redis.ParseURL(uri)
+	require.NoError(t, err)
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	testVehicleRepository(t, NewRedisVehicleRepository(client))
+	testGroupRepository(t, NewRedisGroupRepository(client))
+	testBookingRepository(t, NewRedisBookingRepository(client))
+}
+
+func TestPostgresRepositories(t *testing.T) {
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("wallbox"),
+		tcpostgres.WithUsername("wallbox"),
+		tcpostgres.WithPassword("wallbox"),
+	)
+	if err != nil {
+		t.Skipf("postgres container unavailable: %v", err)
+	}
+	defer container.Terminate(ctx)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE vehicles (id INTEGER PRIMARY KEY, seats INTEGER NOT NULL);
+		CREATE TABLE groups (
+			id INTEGER PRIMARY KEY,
+			people INTEGER NOT NULL,
+			journey_from TIMESTAMPTZ NOT NULL,
+			journey_to TIMESTAMPTZ NOT NULL,
+			enqueued_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE TABLE assignments (
+			group_id INTEGER PRIMARY KEY,
+			booking_id INTEGER NOT NULL,
+			assigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE TABLE bookings (
+			id SERIAL PRIMARY KEY,
+			vehicle_id INTEGER NOT NULL,
+			group_id INTEGER NOT NULL,
+			people INTEGER NOT NULL,
+			journey_from TIMESTAMPTZ NOT NULL,
+			journey_to TIMESTAMPTZ NOT NULL,
+			status TEXT NOT NULL
+		);
+	`)
+	require.NoError(t, err)
+
+	testVehicleRepository(t, NewPostgresVehicleRepository(db))
+	testGroupRepository(t, NewPostgresGroupRepository(db))
+	testBookingRepository(t, NewPostgresBookingRepository(db))
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// Postgres schema backing PostgresVehicleRepository / PostgresGroupRepository
+// / PostgresBookingRepository:
+//
+//	CREATE TABLE vehicles (
+//		id    INTEGER PRIMARY KEY,
+//		seats INTEGER NOT NULL
+//	);
+//
+//	CREATE TABLE groups (
+//		id           INTEGER PRIMARY KEY,
+//		people       INTEGER NOT NULL,
+//		journey_from TIMESTAMPTZ NOT NULL,
+//		journey_to   TIMESTAMPTZ NOT NULL,
+//		enqueued_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+//	);
+//
+//	CREATE TABLE assignments (
+//		group_id    INTEGER PRIMARY KEY,
+//		booking_id  INTEGER NOT NULL,
+//		assigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+//	);
+//
+//	CREATE TABLE bookings (
+//		id           SERIAL PRIMARY KEY,
+//		vehicle_id   INTEGER NOT NULL,
+//		group_id     INTEGER NOT NULL,
+//		people       INTEGER NOT NULL,
+//		journey_from TIMESTAMPTZ NOT NULL,
+//		journey_to   TIMESTAMPTZ NOT NULL,
+//		status       TEXT NOT NULL
+//	);
+//
+// journey_from/journey_to aren't part of the original vehicles/groups/
+// assignments sketch, but Group carries a From/To window since chunk0-3, so
+// the waiting queue needs somewhere durable to keep it; bookings needs the
+// same window to answer OccupiedSeats.
+
+type PostgresVehicleRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresVehicleRepository(db *sql.DB) *PostgresVehicleRepository {
+	return &PostgresVehicleRepository{db: db}
+}
+
+func (repo *PostgresVehicleRepository) GetAllVehicles(ctx context.Context) []Vehicle {
+	rows, err := repo.db.QueryContext(ctx, `SELECT id, seats FROM vehicles ORDER BY id`)
+	if err != nil {
+		log.Printf("postgres: get vehicles: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var vehicles []Vehicle
+	for rows.Next() {
+		var vehicle Vehicle
+		if err := rows.Scan(&vehicle.ID, &vehicle.Seats); err != nil {
+			log.Printf("postgres: scan vehicle: %v", err)
+			continue
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	return vehicles
+}
+
+// SaveVehicles replaces the whole fleet in one transaction so GetAllVehicles
+// never observes a half-written roster.
+func (repo *PostgresVehicleRepository) SaveVehicles(ctx context.Context, vehicles []Vehicle) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("postgres: save vehicles: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vehicles`); err != nil {
+		log.Printf("postgres: clear vehicles: %v", err)
+		return
+	}
+	for _, vehicle := range vehicles {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO vehicles (id, seats) VALUES ($1, $2)`, vehicle.ID, vehicle.Seats); err != nil {
+			log.Printf("postgres: insert vehicle %d: %v", vehicle.ID, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("postgres: commit vehicles: %v", err)
+	}
+}
+
+type PostgresGroupRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresGroupRepository(db *sql.DB) *PostgresGroupRepository {
+	return &PostgresGroupRepository{db: db}
+}
+
+func (repo *PostgresGroupRepository) AddGroup(ctx context.Context, group Group) {
+	_, err := repo.db.ExecContext(ctx,
+		`INSERT INTO groups (id, people, journey_from, journey_to, enqueued_at) VALUES ($1, $2, $3, $4, now())
+		 ON CONFLICT (id) DO UPDATE SET people = EXCLUDED.people, journey_from = EXCLUDED.journey_from, journey_to = EXCLUDED.journey_to`,
+		group.ID, group.People, group.From, group.To)
+	if err != nil {
+		log.Printf("postgres: add group %d: %v", group.ID, err)
+	}
+}
+
+func (repo *PostgresGroupRepository) RemoveGroup(ctx context.Context, groupID int) bool {
+	result, err := repo.db.ExecContext(ctx, `DELETE FROM groups WHERE id = $1`, groupID)
+	if err != nil {
+		log.Printf("postgres: remove group %d: %v", groupID, err)
+		return false
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		return true
+	}
+	if _, err := repo.db.ExecContext(ctx, `DELETE FROM assignments WHERE group_id = $1`, groupID); err != nil {
+		log.Printf("postgres: clear assignment for group %d: %v", groupID, err)
+	}
+	return false
+}
+
+func (repo *PostgresGroupRepository) FindGroup(ctx context.Context, groupID int) (Group, bool) {
+	var group Group
+	err := repo.db.QueryRowContext(ctx,
+		`SELECT id, people, journey_from, journey_to FROM groups WHERE id = $1`, groupID,
+	).Scan(&group.ID, &group.People, &group.From, &group.To)
+	if err != nil {
+		return Group{}, false
+	}
+	return group, true
+}
+
+func (repo *PostgresGroupRepository) GetNextWaitingGroup(ctx context.Context) (Group, bool) {
+	var group Group
+	err := repo.db.QueryRowContext(ctx,
+		`SELECT id, people, journey_from, journey_to FROM groups ORDER BY enqueued_at ASC LIMIT 1`,
+	).Scan(&group.ID, &group.People, &group.From, &group.To)
+	if err != nil {
+		return Group{}, false
+	}
+	return group, true
+}
+
+func (repo *PostgresGroupRepository) WaitingGroups(ctx context.Context) []Group {
+	rows, err := repo.db.QueryContext(ctx,
+		`SELECT id, people, journey_from, journey_to FROM groups ORDER BY enqueued_at ASC`)
+	if err != nil {
+		log.Printf("postgres: waiting groups: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		var group Group
+		if err := rows.Scan(&group.ID, &group.People, &group.From, &group.To); err != nil {
+			log.Printf("postgres: scan waiting group: %v", err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// AssignGroup removes groupID from the waiting queue and records its
+// booking in the same transaction, so a crash mid-assignment can't leave a
+// group counted as both waiting and assigned.
+func (repo *PostgresGroupRepository) AssignGroup(ctx context.Context, groupID, bookingID int) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("postgres: assign group %d: %v", groupID, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM groups WHERE id = $1`, groupID); err != nil {
+		log.Printf("postgres: assign group %d: remove from queue: %v", groupID, err)
+		return
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO assignments (group_id, booking_id, assigned_at) VALUES ($1, $2, now())
+		 ON CONFLICT (group_id) DO UPDATE SET booking_id = EXCLUDED.booking_id, assigned_at = now()`,
+		groupID, bookingID)
+	if err != nil {
+		log.Printf("postgres: assign group %d: record assignment: %v", groupID, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("postgres: assign group %d: commit: %v", groupID, err)
+	}
+}
+
+func (repo *PostgresGroupRepository) GetAssignedBooking(ctx context.Context, groupID int) (int, bool) {
+	var bookingID int
+	err := repo.db.QueryRowContext(ctx, `SELECT booking_id FROM assignments WHERE group_id = $1`, groupID).Scan(&bookingID)
+	if err != nil {
+		return 0, false
+	}
+	return bookingID, true
+}
+
+func (repo *PostgresGroupRepository) UnassignGroup(ctx context.Context, groupID int) {
+	if _, err := repo.db.ExecContext(ctx, `DELETE FROM assignments WHERE group_id = $1`, groupID); err != nil {
+		log.Printf("postgres: unassign group %d: %v", groupID, err)
+	}
+}
+
+func (repo *PostgresGroupRepository) AllAssignments(ctx context.Context) map[int]int {
+	rows, err := repo.db.QueryContext(ctx, `SELECT group_id, booking_id FROM assignments`)
+	if err != nil {
+		log.Printf("postgres: all assignments: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	assignments := make(map[int]int)
+	for rows.Next() {
+		var groupID, bookingID int
+		if err := rows.Scan(&groupID, &bookingID); err != nil {
+			log.Printf("postgres: scan assignment: %v", err)
+			continue
+		}
+		assignments[groupID] = bookingID
+	}
+	return assignments
+}
+
+type PostgresBookingRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBookingRepository(db *sql.DB) *PostgresBookingRepository {
+	return &PostgresBookingRepository{db: db}
+}
+
+func (repo *PostgresBookingRepository) OccupiedSeats(ctx context.Context, vehicleID int, from, to time.Time) int {
+	var occupied int
+	err := repo.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(people), 0) FROM bookings
+		 WHERE vehicle_id = $1 AND status IN ($2, $3) AND journey_from < $5 AND $4 < journey_to`,
+		vehicleID, BookingStatusActive, BookingStatusUnavailable, from, to,
+	).Scan(&occupied)
+	if err != nil {
+		log.Printf("postgres: occupied seats for vehicle %d: %v", vehicleID, err)
+		return 0
+	}
+	return occupied
+}
+
+// Reserve takes a transaction-scoped advisory lock on vehicle.ID so the
+// occupied-seats check and the insert that follows it are atomic with
+// respect to concurrent Reserve calls for the same vehicle.
+func (repo *PostgresBookingRepository) Reserve(ctx context.Context, vehicle Vehicle, groupID, people int, from, to time.Time) (Booking, bool) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("postgres: reserve vehicle %d: %v", vehicle.ID, err)
+		return Booking{}, false
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, vehicle.ID); err != nil {
+		log.Printf("postgres: reserve vehicle %d: lock: %v", vehicle.ID, err)
+		return Booking{}, false
+	}
+
+	var occupied int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(people), 0) FROM bookings
+		 WHERE vehicle_id = $1 AND status IN ($2, $3) AND journey_from < $5 AND $4 < journey_to`,
+		vehicle.ID, BookingStatusActive, BookingStatusUnavailable, from, to,
+	).Scan(&occupied)
+	if err != nil {
+		log.Printf("postgres: reserve vehicle %d: occupied seats: %v", vehicle.ID, err)
+		return Booking{}, false
+	}
+	if vehicle.Seats-occupied < people {
+		return Booking{}, false
+	}
+
+	booking := Booking{VehicleID: vehicle.ID, GroupID: groupID, People: people, From: from, To: to, Status: BookingStatusActive}
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO bookings (vehicle_id, group_id, people, journey_from, journey_to, status)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		booking.VehicleID, booking.GroupID, booking.People, booking.From, booking.To, booking.Status,
+	).Scan(&booking.ID)
+	if err != nil {
+		log.Printf("postgres: reserve vehicle %d: insert: %v", vehicle.ID, err)
+		return Booking{}, false
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("postgres: reserve vehicle %d: commit: %v", vehicle.ID, err)
+		return Booking{}, false
+	}
+	return booking, true
+}
+
+func (repo *PostgresBookingRepository) BlockVehicle(ctx context.Context, vehicleID, seats int, from, to time.Time) Booking {
+	booking := Booking{VehicleID: vehicleID, People: seats, From: from, To: to, Status: BookingStatusUnavailable}
+	err := repo.db.QueryRowContext(ctx,
+		`INSERT INTO bookings (vehicle_id, group_id, people, journey_from, journey_to, status)
+		 VALUES ($1, 0, $2, $3, $4, $5) RETURNING id`,
+		booking.VehicleID, booking.People, booking.From, booking.To, booking.Status,
+	).Scan(&booking.ID)
+	if err != nil {
+		log.Printf("postgres: block vehicle %d: %v", vehicleID, err)
+	}
+	return booking
+}
+
+func (repo *PostgresBookingRepository) GetBooking(ctx context.Context, bookingID int) (Booking, bool) {
+	var booking Booking
+	err := repo.db.QueryRowContext(ctx,
+		`SELECT id, vehicle_id, group_id, people, journey_from, journey_to, status FROM bookings WHERE id = $1`,
+		bookingID,
+	).Scan(&booking.ID, &booking.VehicleID, &booking.GroupID, &booking.People, &booking.From, &booking.To, &booking.Status)
+	if err != nil {
+		return Booking{}, false
+	}
+	return booking, true
+}
+
+func (repo *PostgresBookingRepository) CancelBooking(ctx context.Context, bookingID int) bool {
+	result, err := repo.db.ExecContext(ctx, `UPDATE bookings SET status = $1 WHERE id = $2`, BookingStatusCancelled, bookingID)
+	if err != nil {
+		log.Printf("postgres: cancel booking %d: %v", bookingID, err)
+		return false
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0
+}
+
+func (repo *PostgresBookingRepository) AllBookings(ctx context.Context) []Booking {
+	rows, err := repo.db.QueryContext(ctx,
+		`SELECT id, vehicle_id, group_id, people, journey_from, journey_to, status FROM bookings ORDER BY id`)
+	if err != nil {
+		log.Printf("postgres: all bookings: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var bookings []Booking
+	for rows.Next() {
+		var booking Booking
+		if err := rows.Scan(&booking.ID, &booking.VehicleID, &booking.GroupID, &booking.People, &booking.From, &booking.To, &booking.Status); err != nil {
+			log.Printf("postgres: scan booking: %v", err)
+			continue
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings
+}
+
+func (repo *PostgresBookingRepository) ExpireBookings(ctx context.Context, now time.Time) []Booking {
+	rows, err := repo.db.QueryContext(ctx,
+		`UPDATE bookings SET status = $1 WHERE status IN ($2, $3) AND journey_to <= $4
+		 RETURNING id, vehicle_id, group_id, people, journey_from, journey_to, status`,
+		BookingStatusOld, BookingStatusActive, BookingStatusUnavailable, now,
+	)
+	if err != nil {
+		log.Printf("postgres: expire bookings: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var expired []Booking
+	for rows.Next() {
+		var booking Booking
+		if err := rows.Scan(&booking.ID, &booking.VehicleID, &booking.GroupID, &booking.People, &booking.From, &booking.To, &booking.Status); err != nil {
+			log.Printf("postgres: scan expired booking: %v", err)
+			continue
+		}
+		expired = append(expired, booking)
+	}
+	return expired
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisVehiclesKey     = "wallbox:vehicles"
+	redisWaitQueueKey    = "wallbox:groups:waiting"
+	redisAssignmentsKey  = "wallbox:groups:assignments"
+	redisBookingsKey     = "wallbox:bookings"
+	redisBookingsSeqKey  = "wallbox:bookings:nextid"
+	redisReserveAttempts = 10
+)
+
+// RedisVehicleRepository stores the fleet as a hash keyed by vehicle ID, so
+// GetAllVehicles is a single HGETALL and SaveVehicles replaces the hash
+// wholesale inside a pipeline.
+type RedisVehicleRepository struct {
+	client *redis.Client
+}
+
+func NewRedisVehicleRepository(client *redis.Client) *RedisVehicleRepository {
+	return &RedisVehicleRepository{client: client}
+}
+
+func (repo *RedisVehicleRepository) GetAllVehicles(ctx context.Context) []Vehicle {
+	raw, err := repo.client.HGetAll(ctx, redisVehiclesKey).Result()
+	if err != nil {
+		log.Printf("redis: get vehicles: %v", err)
+		return nil
+	}
+	vehicles := make([]Vehicle, 0, len(raw))
+	for _, value := range raw {
+		var vehicle Vehicle
+		if err := json.Unmarshal([]byte(value), &vehicle); err != nil {
+			log.Printf("redis: decode vehicle: %v", err)
+			continue
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	return vehicles
+}
+
+func (repo *RedisVehicleRepository) SaveVehicles(ctx context.Context, vehicles []Vehicle) {
+	pipe := repo.client.TxPipeline()
+	pipe.Del(ctx, redisVehiclesKey)
+	for _, vehicle := range vehicles {
+		raw, err := json.Marshal(vehicle)
+		if err != nil {
+			log.Printf("redis: encode vehicle %d: %v", vehicle.ID, err)
+			continue
+		}
+		pipe.HSet(ctx, redisVehiclesKey, fmt.Sprint(vehicle.ID), raw)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("redis: save vehicles: %v", err)
+	}
+}
+
+// redisGroupRecord is the on-the-wire shape for a waiting group. Group's
+// From/To are tagged json:"-" so they're excluded from the API payloads;
+// the waiting queue still needs to remember them to re-evaluate the group
+// once capacity frees up, so they're carried separately here.
+type redisGroupRecord struct {
+	ID     int       `json:"id"`
+	People int       `json:"people"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+func toRedisGroupRecord(group Group) redisGroupRecord {
+	return redisGroupRecord{ID: group.ID, People: group.People, From: group.From, To: group.To}
+}
+
+func (record redisGroupRecord) toGroup() Group {
+	return Group{ID: record.ID, People: record.People, From: record.From, To: record.To}
+}
+
+// RedisGroupRepository keeps the waiting queue as a Redis list, pushed with
+// LPUSH and read from the tail so FIFO order is preserved (a group leaves
+// the list through LREM when it is removed or assigned), and assignments as
+// a hash of group ID to booking ID.
+type RedisGroupRepository struct {
+	client *redis.Client
+}
+
+func NewRedisGroupRepository(client *redis.Client) *RedisGroupRepository {
+	return &RedisGroupRepository{client: client}
+}
+
+func (repo *RedisGroupRepository) AddGroup(ctx context.Context, group Group) {
+	raw, err := json.Marshal(toRedisGroupRecord(group))
+	if err != nil {
+		log.Printf("redis: encode group %d: %v", group.ID, err)
+		return
+	}
+	if err := repo.client.LPush(ctx, redisWaitQueueKey, raw).Err(); err != nil {
+		log.Printf("redis: add group %d: %v", group.ID, err)
+	}
+}
+
+func (repo *RedisGroupRepository) RemoveGroup(ctx context.Context, groupID int) bool {
+	items, err := repo.client.LRange(ctx, redisWaitQueueKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("redis: remove group %d: %v", groupID, err)
+		return false
+	}
+	for _, raw := range items {
+		var record redisGroupRecord
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			continue
+		}
+		if record.ID == groupID {
+			repo.client.LRem(ctx, redisWaitQueueKey, 1, raw)
+			return true
+		}
+	}
+	repo.client.HDel(ctx, redisAssignmentsKey, fmt.Sprint(groupID))
+	return false
+}
+
+func (repo *RedisGroupRepository) FindGroup(ctx context.Context, groupID int) (Group, bool) {
+	items, err := repo.client.LRange(ctx, redisWaitQueueKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("redis: find group %d: %v", groupID, err)
+		return Group{}, false
+	}
+	for _, raw := range items {
+		var record redisGroupRecord
+		if err := json.Unmarshal([]byte(raw), &record); err != nil {
+			continue
+		}
+		if record.ID == groupID {
+			return record.toGroup(), true
+		}
+	}
+	return Group{}, false
+}
+
+func (repo *RedisGroupRepository) GetNextWaitingGroup(ctx context.Context) (Group, bool) {
+	raw, err := repo.client.LIndex(ctx, redisWaitQueueKey, -1).Result()
+	if err == redis.Nil {
+		return Group{}, false
+	}
+	if err != nil {
+		log.Printf("redis: get next waiting group: %v", err)
+		return Group{}, false
+	}
+	var record redisGroupRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		log.Printf("redis: decode waiting group: %v", err)
+		return Group{}, false
+	}
+	return record.toGroup(), true
+}
+
+// WaitingGroups returns the queue in FIFO order: the list is pushed with
+// LPUSH, so the oldest entry sits at the tail.
+func (repo *RedisGroupRepository) WaitingGroups(ctx context.Context) []Group {
+	items, err := repo.client.LRange(ctx, redisWaitQueueKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("redis: waiting groups: %v", err)
+		return nil
+	}
+	groups := make([]Group, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		var record redisGroupRecord
+		if err := json.Unmarshal([]byte(items[i]), &record); err != nil {
+			continue
+		}
+		groups = append(groups, record.toGroup())
+	}
+	return groups
+}
+
+func (repo *RedisGroupRepository) AssignGroup(ctx context.Context, groupID, bookingID int) {
+	repo.RemoveGroup(ctx, groupID)
+	if err := repo.client.HSet(ctx, redisAssignmentsKey, fmt.Sprint(groupID), bookingID).Err(); err != nil {
+		log.Printf("redis: assign group %d: %v", groupID, err)
+	}
+}
+
+func (repo *RedisGroupRepository) GetAssignedBooking(ctx context.Context, groupID int) (int, bool) {
+	bookingID, err := repo.client.HGet(ctx, redisAssignmentsKey, fmt.Sprint(groupID)).Int()
+	if err != nil {
+		return 0, false
+	}
+	return bookingID, true
+}
+
+func (repo *RedisGroupRepository) UnassignGroup(ctx context.Context, groupID int) {
+	if err := repo.client.HDel(ctx, redisAssignmentsKey, fmt.Sprint(groupID)).Err(); err != nil {
+		log.Printf("redis: unassign group %d: %v", groupID, err)
+	}
+}
+
+func (repo *RedisGroupRepository) AllAssignments(ctx context.Context) map[int]int {
+	raw, err := repo.client.HGetAll(ctx, redisAssignmentsKey).Result()
+	if err != nil {
+		log.Printf("redis: all assignments: %v", err)
+		return nil
+	}
+	assignments := make(map[int]int, len(raw))
+	for groupIDKey, bookingIDValue := range raw {
+		var groupID, bookingID int
+		if _, err := fmt.Sscan(groupIDKey, &groupID); err != nil {
+			continue
+		}
+		if _, err := fmt.Sscan(bookingIDValue, &bookingID); err != nil {
+			continue
+		}
+		assignments[groupID] = bookingID
+	}
+	return assignments
+}
+
+// RedisBookingRepository keeps bookings in a hash keyed by booking ID, with
+// the ID sequence itself kept in a separate counter key. Reserve needs the
+// occupied-seats check and the insert to be atomic with respect to other
+// Reserve calls, so it runs them inside a WATCH on the bookings hash and
+// retries on conflict, same as an optimistic compare-and-swap.
+type RedisBookingRepository struct {
+	client *redis.Client
+}
+
+func NewRedisBookingRepository(client *redis.Client) *RedisBookingRepository {
+	return &RedisBookingRepository{client: client}
+}
+
+func (repo *RedisBookingRepository) allBookings(ctx context.Context) ([]Booking, error) {
+	raw, err := repo.client.HGetAll(ctx, redisBookingsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	bookings := make([]Booking, 0, len(raw))
+	for _, value := range raw {
+		var booking Booking
+		if err := json.Unmarshal([]byte(value), &booking); err != nil {
+			log.Printf("redis: decode booking: %v", err)
+			continue
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, nil
+}
+
+func occupiedSeats(bookings []Booking, vehicleID int, from, to time.Time) int {
+	occupied := 0
+	for _, booking := range bookings {
+		if booking.VehicleID != vehicleID || !booking.Status.occupiesCapacity() {
+			continue
+		}
+		if overlapsWindow(booking.From, booking.To, from, to) {
+			occupied += booking.People
+		}
+	}
+	return occupied
+}
+
+func (repo *RedisBookingRepository) OccupiedSeats(ctx context.Context, vehicleID int, from, to time.Time) int {
+	bookings, err := repo.allBookings(ctx)
+	if err != nil {
+		log.Printf("redis: occupied seats for vehicle %d: %v", vehicleID, err)
+		return 0
+	}
+	return occupiedSeats(bookings, vehicleID, from, to)
+}
+
+func (repo *RedisBookingRepository) Reserve(ctx context.Context, vehicle Vehicle, groupID, people int, from, to time.Time) (Booking, bool) {
+	var booking Booking
+	var reserved bool
+	for attempt := 0; attempt < redisReserveAttempts; attempt++ {
+		err := repo.client.Watch(ctx, func(tx *redis.Tx) error {
+			bookings, err := repo.allBookings(ctx)
+			if err != nil {
+				return err
+			}
+			if vehicle.Seats-occupiedSeats(bookings, vehicle.ID, from, to) < people {
+				reserved = false
+				return nil
+			}
+			id, err := tx.Incr(ctx, redisBookingsSeqKey).Result()
+			if err != nil {
+				return err
+			}
+			candidate := Booking{ID: int(id), VehicleID: vehicle.ID, GroupID: groupID, People: people, From: from, To: to, Status: BookingStatusActive}
+			raw, err := json.Marshal(candidate)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, redisBookingsKey, fmt.Sprint(candidate.ID), raw)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			booking, reserved = candidate, true
+			return nil
+		}, redisBookingsKey)
+		if err == nil {
+			return booking, reserved
+		}
+		if err != redis.TxFailedErr {
+			log.Printf("redis: reserve vehicle %d: %v", vehicle.ID, err)
+			return Booking{}, false
+		}
+	}
+	log.Printf("redis: reserve vehicle %d: gave up after %d attempts", vehicle.ID, redisReserveAttempts)
+	return Booking{}, false
+}
+
+func (repo *RedisBookingRepository) BlockVehicle(ctx context.Context, vehicleID, seats int, from, to time.Time) Booking {
+	id, err := repo.client.Incr(ctx, redisBookingsSeqKey).Result()
+	if err != nil {
+		log.Printf("redis: block vehicle %d: %v", vehicleID, err)
+		return Booking{}
+	}
+	booking := Booking{ID: int(id), VehicleID: vehicleID, People: seats, From: from, To: to, Status: BookingStatusUnavailable}
+	raw, err := json.Marshal(booking)
+	if err != nil {
+		log.Printf("redis: block vehicle %d: %v", vehicleID, err)
+		return Booking{}
+	}
+	if err := repo.client.HSet(ctx, redisBookingsKey, fmt.Sprint(booking.ID), raw).Err(); err != nil {
+		log.Printf("redis: block vehicle %d: %v", vehicleID, err)
+	}
+	return booking
+}
+
+func (repo *RedisBookingRepository) GetBooking(ctx context.Context, bookingID int) (Booking, bool) {
+	raw, err := repo.client.HGet(ctx, redisBookingsKey, fmt.Sprint(bookingID)).Result()
+	if err != nil {
+		return Booking{}, false
+	}
+	var booking Booking
+	if err := json.Unmarshal([]byte(raw), &booking); err != nil {
+		log.Printf("redis: decode booking %d: %v", bookingID, err)
+		return Booking{}, false
+	}
+	return booking, true
+}
+
+func (repo *RedisBookingRepository) CancelBooking(ctx context.Context, bookingID int) bool {
+	booking, found := repo.GetBooking(ctx, bookingID)
+	if !found {
+		return false
+	}
+	booking.Status = BookingStatusCancelled
+	raw, err := json.Marshal(booking)
+	if err != nil {
+		log.Printf("redis: cancel booking %d: %v", bookingID, err)
+		return false
+	}
+	if err := repo.client.HSet(ctx, redisBookingsKey, fmt.Sprint(bookingID), raw).Err(); err != nil {
+		log.Printf("redis: cancel booking %d: %v", bookingID, err)
+		return false
+	}
+	return true
+}
+
+func (repo *RedisBookingRepository) AllBookings(ctx context.Context) []Booking {
+	bookings, err := repo.allBookings(ctx)
+	if err != nil {
+		log.Printf("redis: all bookings: %v", err)
+		return nil
+	}
+	return bookings
+}
+
+func (repo *RedisBookingRepository) ExpireBookings(ctx context.Context, now time.Time) []Booking {
+	bookings, err := repo.allBookings(ctx)
+	if err != nil {
+		log.Printf("redis: expire bookings: %v", err)
+		return nil
+	}
+	var expired []Booking
+	for _, booking := range bookings {
+		if !booking.Status.occupiesCapacity() || booking.To.After(now) {
+			continue
+		}
+		booking.Status = BookingStatusOld
+		raw, err := json.Marshal(booking)
+		if err != nil {
+			log.Printf("redis: expire booking %d: %v", booking.ID, err)
+			continue
+		}
+		if err := repo.client.HSet(ctx, redisBookingsKey, fmt.Sprint(booking.ID), raw).Err(); err != nil {
+			log.Printf("redis: expire booking %d: %v", booking.ID, err)
+			continue
+		}
+		expired = append(expired, booking)
+	}
+	return expired
+}
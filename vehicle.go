@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+type Vehicle struct {
+	ID    int `json:"id"`
+	Seats int `json:"seats"`
+}
+
+// VehicleRepository tracks the fleet's total capacity. It no longer tracks
+// how many seats are currently free: that's a function of time, derived on
+// demand from BookingRepository for whatever window is being checked.
+type VehicleRepository interface {
+	GetAllVehicles(ctx context.Context) []Vehicle
+	SaveVehicles(ctx context.Context, vehicles []Vehicle)
+}
+
+type InMemoryVehicleRepository struct {
+	mu       sync.RWMutex
+	vehicles []Vehicle
+}
+
+func (repo *InMemoryVehicleRepository) GetAllVehicles(ctx context.Context) []Vehicle {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	snapshot := make([]Vehicle, len(repo.vehicles))
+	copy(snapshot, repo.vehicles)
+	return snapshot
+}
+
+func (repo *InMemoryVehicleRepository) SaveVehicles(ctx context.Context, vehicles []Vehicle) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.vehicles = vehicles
+}